@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"text/template"
 
@@ -36,7 +37,37 @@ func checkHasImps(request *openrtb.BidRequest) error {
 	return nil
 }
 
-func getHeaders(request *openrtb.BidRequest) *http.Header {
+type krushmediaRegsExt struct {
+	GPC       string `json:"gpc,omitempty"`
+	USPrivacy string `json:"us_privacy,omitempty"`
+}
+
+type krushmediaUserExt struct {
+	Consent string `json:"consent,omitempty"`
+}
+
+func extractPrivacySignals(request *openrtb.BidRequest) (gpc string, usPrivacy string, consent string, coppa bool) {
+	if request.Regs != nil {
+		coppa = request.Regs.COPPA == 1
+
+		if len(request.Regs.Ext) > 0 {
+			var regsExt krushmediaRegsExt
+			json.Unmarshal(request.Regs.Ext, &regsExt)
+			gpc = regsExt.GPC
+			usPrivacy = regsExt.USPrivacy
+		}
+	}
+
+	if request.User != nil && len(request.User.Ext) > 0 {
+		var userExt krushmediaUserExt
+		json.Unmarshal(request.User.Ext, &userExt)
+		consent = userExt.Consent
+	}
+
+	return
+}
+
+func getHeaders(request *openrtb.BidRequest, gpc string) *http.Header {
 	headers := http.Header{}
 	headers.Add("Content-Type", "application/json;charset=utf-8")
 	headers.Add("Accept", "application/json")
@@ -60,6 +91,10 @@ func getHeaders(request *openrtb.BidRequest) *http.Header {
 		}
 	}
 
+	if gpc == "1" {
+		headers.Add("Sec-GPC", "1")
+	}
+
 	return &headers
 }
 
@@ -77,39 +112,51 @@ func (a *KrushmediaAdapter) MakeRequests(
 		return nil, []error{err}
 	}
 
+	gpc, usPrivacy, consent, coppa := extractPrivacySignals(&request)
+
 	var errors []error
-	var krushmediaExt *openrtb_ext.ExtKrushmedia
-	var err error
+	impsByAccount := make(map[string][]openrtb.Imp)
+	var accountIDs []string
 
-	for i, imp := range request.Imp {
-		krushmediaExt, err = a.getImpressionExt(&imp)
+	for _, imp := range request.Imp {
+		krushmediaExt, err := a.getImpressionExt(&imp)
 		if err != nil {
 			errors = append(errors, err)
-			break
+			continue
 		}
-		request.Imp[i].Ext = nil
+		imp.Ext = nil
+		if _, ok := impsByAccount[krushmediaExt.AccountID]; !ok {
+			accountIDs = append(accountIDs, krushmediaExt.AccountID)
+		}
+		impsByAccount[krushmediaExt.AccountID] = append(impsByAccount[krushmediaExt.AccountID], imp)
 	}
 
-	if len(errors) > 0 {
-		return nil, errors
-	}
+	for _, accountID := range accountIDs {
+		accountRequest := request
+		accountRequest.Imp = impsByAccount[accountID]
 
-	url, err := a.buildEndpointURL(krushmediaExt)
-	if err != nil {
-		return nil, []error{err}
-	}
+		url, err := a.buildEndpointURL(&openrtb_ext.ExtKrushmedia{AccountID: accountID})
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		url = appendPrivacyParams(url, usPrivacy, consent, coppa)
 
-	reqJSON, err := json.Marshal(request)
-	if err != nil {
-		return nil, []error{err}
+		reqJSON, err := json.Marshal(accountRequest)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+
+		requestsToBidder = append(requestsToBidder, &adapters.RequestData{
+			Method:  http.MethodPost,
+			Body:    reqJSON,
+			Uri:     url,
+			Headers: *getHeaders(&accountRequest, gpc),
+		})
 	}
 
-	return []*adapters.RequestData{{
-		Method:  http.MethodPost,
-		Body:    reqJSON,
-		Uri:     url,
-		Headers: *getHeaders(&request),
-	}}, nil
+	return requestsToBidder, errors
 }
 
 func (a *KrushmediaAdapter) getImpressionExt(imp *openrtb.Imp) (*openrtb_ext.ExtKrushmedia, error) {
@@ -133,6 +180,27 @@ func (a *KrushmediaAdapter) buildEndpointURL(params *openrtb_ext.ExtKrushmedia)
 	return macros.ResolveMacros(a.endpoint, endpointParams)
 }
 
+func appendPrivacyParams(endpoint string, usPrivacy string, consent string, coppa bool) string {
+	parsedURL, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+
+	query := parsedURL.Query()
+	if len(usPrivacy) > 0 {
+		query.Set("us_privacy", usPrivacy)
+	}
+	if len(consent) > 0 {
+		query.Set("gdpr_consent", consent)
+	}
+	if coppa {
+		query.Set("coppa", "1")
+	}
+	parsedURL.RawQuery = query.Encode()
+
+	return parsedURL.String()
+}
+
 func (a *KrushmediaAdapter) CheckResponseStatusCodes(response *adapters.ResponseData) error {
 	if response.StatusCode == http.StatusNoContent {
 		return nil
@@ -178,29 +246,47 @@ func (a *KrushmediaAdapter) MakeBids(
 		}}
 	}
 
-	bidResponse := adapters.NewBidderResponseWithBidsCapacity(len(bidResp.SeatBid[0].Bid))
-	sb := bidResp.SeatBid[0]
+	if len(bidResp.SeatBid) == 0 {
+		return adapters.NewBidderResponseWithBidsCapacity(0), nil
+	}
 
-	for _, bid := range sb.Bid {
-		bidResponse.Bids = append(bidResponse.Bids, &adapters.TypedBid{
-			Bid:     &bid,
-			BidType: getMediaTypeForImp(bid.ImpID, openRTBRequest.Imp),
-		})
+	bidCount := 0
+	for _, sb := range bidResp.SeatBid {
+		bidCount += len(sb.Bid)
+	}
+
+	var errors []error
+	bidResponse := adapters.NewBidderResponseWithBidsCapacity(bidCount)
+
+	for _, sb := range bidResp.SeatBid {
+		for i := range sb.Bid {
+			bid := sb.Bid[i]
+			bidType, err := getMediaTypeForImp(bid.ImpID, openRTBRequest.Imp)
+			if err != nil {
+				errors = append(errors, err)
+				continue
+			}
+			bidResponse.Bids = append(bidResponse.Bids, &adapters.TypedBid{
+				Bid:     &bid,
+				BidType: bidType,
+			})
+		}
 	}
-	return bidResponse, nil
+	return bidResponse, errors
 }
 
-func getMediaTypeForImp(impId string, imps []openrtb.Imp) openrtb_ext.BidType {
-	mediaType := openrtb_ext.BidTypeBanner
+func getMediaTypeForImp(impId string, imps []openrtb.Imp) (openrtb_ext.BidType, error) {
 	for _, imp := range imps {
 		if imp.ID == impId {
 			if imp.Video != nil {
-				mediaType = openrtb_ext.BidTypeVideo
+				return openrtb_ext.BidTypeVideo, nil
 			} else if imp.Native != nil {
-				mediaType = openrtb_ext.BidTypeNative
+				return openrtb_ext.BidTypeNative, nil
 			}
-			return mediaType
+			return openrtb_ext.BidTypeBanner, nil
 		}
 	}
-	return mediaType
-}
\ No newline at end of file
+	return "", &errortypes.BadServerResponse{
+		Message: fmt.Sprintf("Failed to find impression \"%s\" ", impId),
+	}
+}
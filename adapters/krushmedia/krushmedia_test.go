@@ -0,0 +1,274 @@
+package krushmedia
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"text/template"
+
+	"github.com/mxmCherry/openrtb"
+	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/privacy"
+	"github.com/prebid/prebid-server/privacy/ccpa"
+	"github.com/prebid/prebid-server/privacy/gdpr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractPrivacySignals(t *testing.T) {
+	regsExt, err := json.Marshal(krushmediaRegsExt{GPC: "1", USPrivacy: "1YNY"})
+	if err != nil {
+		t.Fatalf("failed to marshal regs ext: %v", err)
+	}
+	userExt, err := json.Marshal(krushmediaUserExt{Consent: "consentstring"})
+	if err != nil {
+		t.Fatalf("failed to marshal user ext: %v", err)
+	}
+
+	request := &openrtb.BidRequest{
+		Regs: &openrtb.Regs{COPPA: 1, Ext: regsExt},
+		User: &openrtb.User{Ext: userExt},
+	}
+
+	gpc, usPrivacy, consent, coppa := extractPrivacySignals(request)
+
+	if gpc != "1" {
+		t.Errorf("expected gpc \"1\", got %q", gpc)
+	}
+	if usPrivacy != "1YNY" {
+		t.Errorf("expected usPrivacy \"1YNY\", got %q", usPrivacy)
+	}
+	if consent != "consentstring" {
+		t.Errorf("expected consent \"consentstring\", got %q", consent)
+	}
+	if !coppa {
+		t.Errorf("expected coppa true")
+	}
+}
+
+func TestExtractPrivacySignalsKeepsValidFieldsAfterSiblingTypeMismatch(t *testing.T) {
+	request := &openrtb.BidRequest{
+		Regs: &openrtb.Regs{Ext: json.RawMessage(`{"us_privacy":"1YNY","gpc":true}`)},
+	}
+
+	_, usPrivacy, _, _ := extractPrivacySignals(request)
+
+	if usPrivacy != "1YNY" {
+		t.Errorf("expected usPrivacy \"1YNY\" to survive a sibling field type mismatch, got %q", usPrivacy)
+	}
+}
+
+func TestGetHeadersSetsSecGPC(t *testing.T) {
+	request := &openrtb.BidRequest{}
+
+	headers := getHeaders(request, "1")
+
+	if got := headers.Get("Sec-GPC"); got != "1" {
+		t.Errorf("expected Sec-GPC header \"1\", got %q", got)
+	}
+}
+
+func TestGetHeadersOmitsSecGPCWhenUnset(t *testing.T) {
+	request := &openrtb.BidRequest{}
+
+	headers := getHeaders(request, "")
+
+	if got := headers.Get("Sec-GPC"); got != "" {
+		t.Errorf("expected no Sec-GPC header, got %q", got)
+	}
+}
+
+func TestAppendPrivacyParams(t *testing.T) {
+	endpoint := appendPrivacyParams("https://sync.krushmedia.com/rtb", "1YNY", "consentstring", true)
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		t.Fatalf("failed to parse resulting url: %v", err)
+	}
+	query := parsed.Query()
+
+	if got := query.Get("us_privacy"); got != "1YNY" {
+		t.Errorf("expected us_privacy=1YNY, got %q", got)
+	}
+	if got := query.Get("gdpr_consent"); got != "consentstring" {
+		t.Errorf("expected gdpr_consent=consentstring, got %q", got)
+	}
+	if got := query.Get("coppa"); got != "1" {
+		t.Errorf("expected coppa=1, got %q", got)
+	}
+}
+
+func TestAppendPrivacyParamsOmitsUnsetSignals(t *testing.T) {
+	endpoint := appendPrivacyParams("https://sync.krushmedia.com/rtb", "", "", false)
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		t.Fatalf("failed to parse resulting url: %v", err)
+	}
+	query := parsed.Query()
+
+	if len(query) != 0 {
+		t.Errorf("expected no privacy query params, got %v", query)
+	}
+}
+
+func TestMakeBidsEmptySeatBid(t *testing.T) {
+	a := &KrushmediaAdapter{}
+	request := &openrtb.BidRequest{}
+	body, err := json.Marshal(openrtb.BidResponse{SeatBid: []openrtb.SeatBid{}})
+	if err != nil {
+		t.Fatalf("failed to marshal bid response: %v", err)
+	}
+	response := &adapters.ResponseData{StatusCode: http.StatusOK, Body: body}
+
+	bidderResponse, errs := a.MakeBids(request, &adapters.RequestData{}, response)
+
+	if len(errs) != 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+	if bidderResponse == nil || len(bidderResponse.Bids) != 0 {
+		t.Fatalf("expected 0 bids, got %+v", bidderResponse)
+	}
+}
+
+func TestMakeBidsAccumulatesMultipleSeatBids(t *testing.T) {
+	a := &KrushmediaAdapter{}
+	request := &openrtb.BidRequest{
+		Imp: []openrtb.Imp{
+			{ID: "imp1", Banner: &openrtb.Banner{}},
+			{ID: "imp2", Banner: &openrtb.Banner{}},
+		},
+	}
+	body, err := json.Marshal(openrtb.BidResponse{
+		SeatBid: []openrtb.SeatBid{
+			{Bid: []openrtb.Bid{{ID: "bid1", ImpID: "imp1"}}},
+			{Bid: []openrtb.Bid{{ID: "bid2", ImpID: "imp2"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal bid response: %v", err)
+	}
+	response := &adapters.ResponseData{StatusCode: http.StatusOK, Body: body}
+
+	bidderResponse, errs := a.MakeBids(request, &adapters.RequestData{}, response)
+
+	if len(errs) != 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+	if len(bidderResponse.Bids) != 2 {
+		t.Fatalf("expected 2 bids accumulated across seatbids, got %d", len(bidderResponse.Bids))
+	}
+}
+
+func TestMakeBidsDropsBidWithUnknownImpID(t *testing.T) {
+	a := &KrushmediaAdapter{}
+	request := &openrtb.BidRequest{
+		Imp: []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{}}},
+	}
+	body, err := json.Marshal(openrtb.BidResponse{
+		SeatBid: []openrtb.SeatBid{
+			{Bid: []openrtb.Bid{
+				{ID: "bid1", ImpID: "imp1"},
+				{ID: "bid2", ImpID: "unknown"},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal bid response: %v", err)
+	}
+	response := &adapters.ResponseData{StatusCode: http.StatusOK, Body: body}
+
+	bidderResponse, errs := a.MakeBids(request, &adapters.RequestData{}, response)
+
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error for the unmapped impression, got %d: %v", len(errs), errs)
+	}
+	if len(bidderResponse.Bids) != 1 {
+		t.Fatalf("expected the sibling bid to still come back, got %d bids", len(bidderResponse.Bids))
+	}
+	if bidderResponse.Bids[0].Bid.ID != "bid1" {
+		t.Errorf("expected surviving bid to be bid1, got %s", bidderResponse.Bids[0].Bid.ID)
+	}
+}
+
+func TestMakeRequestsSplitsByAccountID(t *testing.T) {
+	endpointTemplate, err := template.New("endpointTemplate").Parse("https://{{.AccountID}}.krushmedia.com/bid")
+	if err != nil {
+		t.Fatalf("failed to parse endpoint template: %v", err)
+	}
+	a := &KrushmediaAdapter{endpoint: *endpointTemplate}
+
+	request := &openrtb.BidRequest{
+		Imp: []openrtb.Imp{
+			{ID: "imp1", Ext: json.RawMessage(`{"bidder":{"accountId":"111"}}`)},
+			{ID: "imp2", Ext: json.RawMessage(`{"bidder":{"accountId":"222"}}`)},
+			{ID: "imp3", Ext: json.RawMessage(`{"bidder":{"accountId":"111"}}`)},
+		},
+	}
+
+	reqsToBidder, errs := a.MakeRequests(request, &adapters.ExtraRequestInfo{})
+
+	if len(errs) != 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+	if len(reqsToBidder) != 2 {
+		t.Fatalf("expected 2 requests, one per account, got %d", len(reqsToBidder))
+	}
+
+	impCountByURL := map[string]int{}
+	for _, r := range reqsToBidder {
+		var splitRequest openrtb.BidRequest
+		if err := json.Unmarshal(r.Body, &splitRequest); err != nil {
+			t.Fatalf("failed to unmarshal split request body: %v", err)
+		}
+		impCountByURL[r.Uri] = len(splitRequest.Imp)
+	}
+
+	if got := impCountByURL["https://111.krushmedia.com/bid"]; got != 2 {
+		t.Errorf("expected account 111 request to carry 2 imps, got %d", got)
+	}
+	if got := impCountByURL["https://222.krushmedia.com/bid"]; got != 1 {
+		t.Errorf("expected account 222 request to carry 1 imp, got %d", got)
+	}
+}
+
+func TestMakeRequestsAccumulatesErrorsForMalformedExt(t *testing.T) {
+	endpointTemplate, err := template.New("endpointTemplate").Parse("https://{{.AccountID}}.krushmedia.com/bid")
+	if err != nil {
+		t.Fatalf("failed to parse endpoint template: %v", err)
+	}
+	a := &KrushmediaAdapter{endpoint: *endpointTemplate}
+
+	request := &openrtb.BidRequest{
+		Imp: []openrtb.Imp{
+			{ID: "imp1", Ext: json.RawMessage(`{"bidder":{"accountId":"111"}}`)},
+			{ID: "imp2", Ext: json.RawMessage(`not-json`)},
+		},
+	}
+
+	reqsToBidder, errs := a.MakeRequests(request, &adapters.ExtraRequestInfo{})
+
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error for the malformed imp ext, got %d: %v", len(errs), errs)
+	}
+	if len(reqsToBidder) != 1 {
+		t.Fatalf("expected the valid imp to still produce a request, got %d", len(reqsToBidder))
+	}
+}
+
+func TestNewKrushmediaSyncer(t *testing.T) {
+	syncURL := "https://sync.krushmedia.com/s2s/sync?gdpr={{.GDPR}}&gdpr_consent={{.GDPRConsent}}&us_privacy={{.USPrivacy}}&redirect=https%3A%2F%2Fprebid.org%2Fsetuid%3Fbidder%3Dkrushmedia%26uid%3D%24UID"
+	syncURLTemplate := template.Must(template.New("sync-template").Parse(syncURL))
+
+	syncer := NewKrushmediaSyncer(syncURLTemplate)
+	syncInfo, err := syncer.GetUsersyncInfo(privacy.Policies{
+		GDPR: gdpr.Policy{Signal: "1", Consent: "BONV8oqONXwgmADACHENAI4AAAB9vABAASA"},
+		CCPA: ccpa.Policy{Consent: "1NYN"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://sync.krushmedia.com/s2s/sync?gdpr=1&gdpr_consent=BONV8oqONXwgmADACHENAI4AAAB9vABAASA&us_privacy=1NYN&redirect=https%3A%2F%2Fprebid.org%2Fsetuid%3Fbidder%3Dkrushmedia%26uid%3D%24UID", syncInfo.URL)
+	assert.Equal(t, "redirect", syncInfo.Type)
+	assert.EqualValues(t, 0, syncer.GDPRVendorID())
+	assert.False(t, syncInfo.SupportCORS)
+}
@@ -0,0 +1,11 @@
+package krushmedia
+
+import (
+	"text/template"
+
+	"github.com/prebid/prebid-server/usersync"
+)
+
+func NewKrushmediaSyncer(temp *template.Template) usersync.Usersyncer {
+	return usersync.NewSyncer("krushmedia", 0, temp, usersync.SyncTypeRedirect)
+}